@@ -0,0 +1,30 @@
+// step-gen-man walks the step command tree and writes one step-<subcommand>.1
+// roff file per node into the current directory, ready to be installed
+// alongside the binary or packaged for a man-db.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+	"go.step.sm/cli-utils/command"
+
+	"github.com/smallstep/cli/usage"
+
+	_ "github.com/smallstep/cli/command/rand"
+)
+
+func main() {
+	app := cli.NewApp()
+	app.HelpName = "step"
+	app.Commands = command.Retrieve()
+
+	for name, page := range usage.GenerateMan(app, 1) {
+		path := name + ".1"
+		if err := os.WriteFile(path, page, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
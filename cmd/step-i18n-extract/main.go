@@ -0,0 +1,88 @@
+// step-i18n-extract walks every registered step command and emits
+// messages.gotext.json, the source catalog golang.org/x/text/cmd/gotext
+// turns into one editable message file per locale. Those locale files are
+// read from a directory at run time by usage.LoadCatalog, which builds the
+// catalog with catalog.NewBuilder, and installed via usage.RegisterCatalog
+// (usage.InitI18n does both); they are not embedded in the binary.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+	"go.step.sm/cli-utils/command"
+
+	"github.com/smallstep/cli/usage"
+
+	_ "github.com/smallstep/cli/command/rand"
+)
+
+// goTextMessage is one entry of the messages.gotext.json format.
+type goTextMessage struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Commands = command.Retrieve()
+
+	var messages []goTextMessage
+	seen := make(map[string]bool)
+	add := func(id, text string) {
+		if text == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		messages = append(messages, goTextMessage{ID: id, Message: text})
+	}
+
+	// Key by leaf command name, not node.Path: translateCommandField only
+	// ever sees a bare *cli.Command at render time, with no reliable way to
+	// recover its position in the tree, so it looks up the same
+	// "usage.command.<name>.<field>" key regardless of depth. Two commands
+	// sharing a leaf name under different parents (rare in practice) will
+	// collide and share one translation; add (via seen) keeps the first.
+	for _, node := range usage.Walk(app) {
+		add("usage.command."+node.Command.Name+".usage", node.Command.Usage)
+		add("usage.command."+node.Command.Name+".description", node.Command.Description)
+		for _, f := range node.Command.Flags {
+			name := strings.SplitN(f.GetName(), ",", 2)[0]
+			add("usage.flag."+name+".usage", flagUsage(f))
+		}
+	}
+
+	out, err := os.Create("messages.gotext.json")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer out.Close()
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(struct {
+		Language string          `json:"language"`
+		Messages []goTextMessage `json:"messages"`
+	}{Language: "en", Messages: messages})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func flagUsage(f cli.Flag) string {
+	switch fl := f.(type) {
+	case cli.StringFlag:
+		return fl.Usage
+	case cli.BoolFlag:
+		return fl.Usage
+	case cli.IntFlag:
+		return fl.Usage
+	default:
+		return ""
+	}
+}
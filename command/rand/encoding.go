@@ -0,0 +1,117 @@
+package rand
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"go.step.sm/crypto/randutil"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const zBase32Alphabet = "ybndrfg8ejkmcpqxot1uwisza345h769"
+
+// randomBase32 returns length characters of RFC 4648 base32 (or its "hex"
+// variant), lowercase and unpadded.
+func randomBase32(length int, hex bool) (string, error) {
+	enc := base32.StdEncoding
+	if hex {
+		enc = base32.HexEncoding
+	}
+	enc = enc.WithPadding(base32.NoPadding)
+
+	b, err := randutil.Bytes(int(math.Ceil(float64(length) * math.Log2(32) / 8)))
+	if err != nil {
+		return "", err
+	}
+	return truncate(strings.ToLower(enc.EncodeToString(b)), length)
+}
+
+// randomBase64 returns length characters of unpadded base64, standard or
+// URL-safe.
+func randomBase64(length int, urlSafe bool) (string, error) {
+	enc := base64.RawStdEncoding
+	if urlSafe {
+		enc = base64.RawURLEncoding
+	}
+
+	b, err := randutil.Bytes(int(math.Ceil(float64(length) * math.Log2(64) / 8)))
+	if err != nil {
+		return "", err
+	}
+	return truncate(enc.EncodeToString(b), length)
+}
+
+// randomBase58 returns length characters of Bitcoin-alphabet base58.
+func randomBase58(length int) (string, error) {
+	b, err := randutil.Bytes(int(math.Ceil(float64(length) * math.Log2(58) / 8)))
+	if err != nil {
+		return "", err
+	}
+
+	n := new(big.Int).SetBytes(b)
+	base := big.NewInt(int64(len(base58Alphabet)))
+	mod := new(big.Int)
+
+	var sb strings.Builder
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		sb.WriteByte(base58Alphabet[mod.Int64()])
+	}
+	// big.Int.SetBytes drops leading 0x00 bytes, which would otherwise each
+	// silently shrink the encoded length by ~1.4 base58 digits; restore them
+	// as leading '1's, the standard base58 convention.
+	for _, by := range b {
+		if by != 0 {
+			break
+		}
+		sb.WriteByte(base58Alphabet[0])
+	}
+	s := reverse(sb.String())
+	if len(s) < length {
+		// A draw with a small leading byte can encode to fewer digits than
+		// the byte budget usually yields; draw again instead of failing.
+		return randomBase58(length)
+	}
+	return truncate(s, length)
+}
+
+// randomZBase32 returns length characters of z-base-32, the human-oriented
+// base32 variant used by e.g. Tahoe-LAFS.
+func randomZBase32(length int) (string, error) {
+	b, err := randutil.Bytes(int(math.Ceil(float64(length) * 5 / 8)))
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	buf, bufBits := 0, 0
+	for _, by := range b {
+		buf = buf<<8 | int(by)
+		bufBits += 8
+		for bufBits >= 5 {
+			bufBits -= 5
+			sb.WriteByte(zBase32Alphabet[(buf>>uint(bufBits))&0x1f])
+		}
+	}
+	return truncate(sb.String(), length)
+}
+
+func truncate(s string, length int) (string, error) {
+	if len(s) < length {
+		return "", fmt.Errorf("rand: not enough random data to produce %d characters", length)
+	}
+	return s[:length], nil
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
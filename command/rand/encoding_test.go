@@ -0,0 +1,56 @@
+package rand
+
+import "testing"
+
+func TestRandomEncodingsLength(t *testing.T) {
+	// Every length here has been hit by a truncate() failure at least once
+	// during development (short reads from a too-tight byte budget), so
+	// these are run many times rather than once.
+	lengths := []int{1, 2, 7, 10, 16, 37, 64}
+	const trials = 500
+
+	generators := map[string]func(int) (string, error){
+		"base32":    func(n int) (string, error) { return randomBase32(n, false) },
+		"base32hex": func(n int) (string, error) { return randomBase32(n, true) },
+		"base64":    func(n int) (string, error) { return randomBase64(n, false) },
+		"base64url": func(n int) (string, error) { return randomBase64(n, true) },
+		"base58":    randomBase58,
+		"z-base-32": randomZBase32,
+	}
+
+	for name, gen := range generators {
+		for _, length := range lengths {
+			for i := 0; i < trials; i++ {
+				s, err := gen(length)
+				if err != nil {
+					t.Fatalf("%s(%d) trial %d: unexpected error: %v", name, length, i, err)
+				}
+				if len(s) != length {
+					t.Fatalf("%s(%d) trial %d: got %d characters (%q), want %d", name, length, i, len(s), s, length)
+				}
+			}
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if _, err := truncate("ab", 3); err == nil {
+		t.Error("truncate() with short input error = nil, want error")
+	}
+	s, err := truncate("abcdef", 3)
+	if err != nil {
+		t.Fatalf("truncate() error = %v", err)
+	}
+	if s != "abc" {
+		t.Errorf("truncate() = %q, want %q", s, "abc")
+	}
+}
+
+func TestReverse(t *testing.T) {
+	if got := reverse("abc"); got != "cba" {
+		t.Errorf("reverse(%q) = %q, want %q", "abc", got, "cba")
+	}
+	if got := reverse(""); got != "" {
+		t.Errorf("reverse(empty) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,96 @@
+package rand
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+	"go.step.sm/cli-utils/errs"
+	"go.step.sm/crypto/randutil"
+)
+
+//go:embed wordlists/bip39_english.txt
+var bip39EnglishWordlist string
+
+// wordsToEntropyBits maps the --words convenience alias to the entropy bit
+// count BIP-39 derives it from: a 12/15/18/21/24-word mnemonic always comes
+// from 128/160/192/224/256 bits of entropy plus its checksum.
+var wordsToEntropyBits = map[int]int{
+	12: 128,
+	15: 160,
+	18: 192,
+	21: 224,
+	24: 256,
+}
+
+// mnemonicEntropyBits resolves the entropy bit count for mnemonic mode: the
+// --words convenience alias takes precedence over <length> when set.
+func mnemonicEntropyBits(ctx *cli.Context, length int) (int, error) {
+	if !ctx.IsSet("words") {
+		return length, nil
+	}
+	bits, ok := wordsToEntropyBits[ctx.Int("words")]
+	if !ok {
+		return 0, errs.InvalidFlagValue(ctx, "words", strconv.Itoa(ctx.Int("words")), "12, 15, 18, 21, 24")
+	}
+	return bits, nil
+}
+
+// randMnemonic implements BIP-39: it draws entropyBits of entropy, appends a
+// checksum of entropyBits/32 bits taken from the high end of
+// SHA-256(entropy), and maps each resulting 11-bit group to a word in the
+// standard English wordlist.
+func randMnemonic(ctx *cli.Context, entropyBits int) error {
+	if entropyBits == 0 {
+		entropyBits = 128
+	}
+	switch entropyBits {
+	case 128, 160, 192, 224, 256:
+	default:
+		return errs.InvalidFlagValue(ctx, "format", "mnemonic", "length must be one of 128, 160, 192, 224, or 256 (entropy bits)")
+	}
+
+	words := splitWords(bip39EnglishWordlist)
+	if len(words) != 2048 {
+		return fmt.Errorf("bip-39 english wordlist must have 2048 words, got %d", len(words))
+	}
+
+	entropy, err := randutil.Bytes(entropyBits / 8)
+	if err != nil {
+		return err
+	}
+	checksum := sha256.Sum256(entropy)
+	checksumBits := entropyBits / 32
+
+	stream := appendBits(nil, entropy, entropyBits)
+	stream = appendBits(stream, checksum[:], checksumBits)
+
+	mnemonic := make([]string, 0, len(stream)/11)
+	for i := 0; i < len(stream); i += 11 {
+		mnemonic = append(mnemonic, words[bitsToIndex(stream[i:i+11])])
+	}
+
+	return printResult(ctx, strings.Join(mnemonic, " "), "", "mnemonic", len(mnemonic), float64(entropyBits))
+}
+
+// appendBits appends the first n bits of b, most significant bit first, to
+// dst as individual 0/1 bytes so they can be regrouped into the 11-bit
+// chunks BIP-39 words are indexed by.
+func appendBits(dst []byte, b []byte, n int) []byte {
+	for i := 0; i < n; i++ {
+		bit := (b[i/8] >> uint(7-i%8)) & 1
+		dst = append(dst, bit)
+	}
+	return dst
+}
+
+func bitsToIndex(bits []byte) int {
+	v := 0
+	for _, b := range bits {
+		v = v<<1 | int(b)
+	}
+	return v
+}
@@ -0,0 +1,33 @@
+package rand
+
+import "testing"
+
+func TestAppendBitsBitsToIndexRoundTrip(t *testing.T) {
+	b := []byte{0b10110100, 0b01011010}
+	stream := appendBits(nil, b, 16)
+	if len(stream) != 16 {
+		t.Fatalf("appendBits() produced %d bits, want 16", len(stream))
+	}
+
+	// Regrouping into 11-bit chunks and back should reproduce the same bit
+	// pattern bitsToIndex was built to index BIP-39 words by.
+	if got := bitsToIndex(stream[:11]); got != 0b10110100010 {
+		t.Errorf("bitsToIndex(first 11 bits) = %011b, want %011b", got, 0b10110100010)
+	}
+}
+
+func TestWordsToEntropyBitsMatchesWordCount(t *testing.T) {
+	// BIP-39 appends entropyBits/32 checksum bits, then groups the result
+	// into 11-bit words; wordsToEntropyBits must map each word count to the
+	// entropy that makes that division come out exact.
+	for words, entropyBits := range wordsToEntropyBits {
+		total := entropyBits + entropyBits/32
+		if total%11 != 0 {
+			t.Errorf("entropyBits=%d: %d total bits not divisible by 11", entropyBits, total)
+			continue
+		}
+		if got := total / 11; got != words {
+			t.Errorf("entropyBits=%d yields %d words, want %d", entropyBits, got, words)
+		}
+	}
+}
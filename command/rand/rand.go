@@ -1,8 +1,8 @@
 package rand
 
 import (
-	"bufio"
 	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"math/big"
 	"os"
@@ -21,7 +21,7 @@ func init() {
 		Name:      "rand",
 		Action:    command.ActionFunc(randAction),
 		Usage:     "generate random strings",
-		UsageText: "**step rand** [<length>] [--format=<format>] [--dictionary=<file>]",
+		UsageText: "**step rand** [<length>] [--format=<format>] [--dictionary=<file>] [--wordlist=<name>]",
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "format",
@@ -31,6 +31,40 @@ func init() {
 				Name:  "dictionary,dict",
 				Usage: "The <file> to use as a dictionary to get random words.",
 			},
+			cli.StringFlag{
+				Name:  "wordlist",
+				Usage: "The <name> of the built-in wordlist to draw passphrase words from: eff-large, eff-short1, eff-short2, or a path to a custom file. An alternative to --dictionary.",
+			},
+			cli.StringFlag{
+				Name:  "separator",
+				Value: "-",
+				Usage: "The <character> used to join words in a passphrase.",
+			},
+			cli.BoolFlag{
+				Name:  "capitalize",
+				Usage: "Capitalize the first letter of each word in a passphrase.",
+			},
+			cli.StringFlag{
+				Name:  "min-entropy",
+				Usage: "The minimum <bits> of entropy the passphrase must have. Overrides <length> with the smallest word count that reaches it.",
+			},
+			cli.IntFlag{
+				Name:  "min-dictionary-size",
+				Value: defaultMinDictionarySize,
+				Usage: "The minimum <n> of unique words a --dictionary or --wordlist must have, unless --allow-weak-dictionary is set.",
+			},
+			cli.BoolFlag{
+				Name:  "allow-weak-dictionary",
+				Usage: fmt.Sprintf("Allow a --dictionary or --wordlist with fewer unique words than --min-dictionary-size requires (default %d).", defaultMinDictionarySize),
+			},
+			cli.BoolFlag{
+				Name:  "json",
+				Usage: "Write the output as a JSON object with the value, format, length, and entropy in bits. With --format raw, value is base64-encoded.",
+			},
+			cli.IntFlag{
+				Name:  "words",
+				Usage: "With --format mnemonic, the <n> words to generate (12, 15, 18, 21, or 24) instead of specifying <length> as entropy bits.",
+			},
 		},
 		Description: `**step rand** generates random strings that can be used for multiple purposes.
 The <rand> command supports printing stings with different formats. It defaults
@@ -55,15 +89,27 @@ The list of supported formats is the following:
   (A-Z).
 * emoji: uses a curated list of 256 emojis that are not entirely similar.
 * raw: uses random bytes.
+* base32: RFC 4648 base32, lowercase and unpadded.
+* base32hex: the RFC 4648 "Extended Hex" base32 alphabet, lowercase and unpadded.
+* base64: RFC 4648 base64, unpadded.
+* base64url: RFC 4648 URL-safe base64, unpadded.
+* base58: the Bitcoin base58 alphabet.
+* z-base-32: the human-oriented base32 alphabet used by e.g. Tahoe-LAFS.
 
 The following special formats are also supported:
 
 * dice: generates a random number between 1 and 6 or the given argument,
 * uuid: generates a UUIDv4.
+* mnemonic: generates a BIP-39 mnemonic. <length> is the entropy in bits (one
+  of 128, 160, 192, 224, or 256; defaults to 128), or use --words to specify
+  the number of words instead (12, 15, 18, 21, or 24).
+
+When --dictionary or --wordlist is used, <rand> prints a summary of the
+resulting passphrase's entropy to stderr.
 
 ## POSITIONAL ARGUMENTS
 
-<length>
+[<length>]
 :  The length of the random string in characters or words. If the dice format
 is used, the length is the maximum number of the dice.
 
@@ -81,6 +127,13 @@ $ step rand --dictionary words.txt
 scalpel-elan-fulsome-BELT-warring-balcony
 '''
 
+Generate a passphrase from the EFF large wordlist with at least 80 bits of entropy:
+'''
+$ step rand --wordlist eff-large --min-entropy 80
+entropy: 7 words from a 7776-word list ≈ 90.5 bits
+chilly-unplug-chivalry-thrift-eventual-rash-unbalance
+'''
+
 Generates a random roll of dice:
 '''
 $ step rand --format dice
@@ -113,15 +166,25 @@ func randAction(ctx *cli.Context) error {
 	)
 
 	dictionary := ctx.String("dictionary")
+	wordlist := ctx.String("wordlist")
 	format := strings.ToLower(ctx.String("format"))
 
-	// Default to 32 characters, 6 words if a dictionary is used, or a dice roll
-	// between 1 and 6.
+	if dictionary != "" && wordlist != "" {
+		return errs.IncompatibleFlagWithFlag(ctx, "dictionary", "wordlist")
+	}
+	if wordlist != "" {
+		dictionary = wordlist
+	}
+
+	// Default to 32 characters, 6 words if a dictionary is used, a dice roll
+	// between 1 and 6, or 128 bits of entropy for a mnemonic.
 	switch {
 	case dictionary != "" && format != "":
 		return errs.IncompatibleFlagWithFlag(ctx, "format", "dictionary")
 	case dictionary != "", format == "dice":
 		length = 6
+	case format == "mnemonic":
+		length = 128
 	default:
 		length = 32
 	}
@@ -135,7 +198,7 @@ func randAction(ctx *cli.Context) error {
 	}
 
 	if dictionary != "" {
-		return randWithDictionary(dictionary, length)
+		return randWithDictionary(ctx, dictionary, length)
 	}
 
 	return randWithFormat(ctx, format, length)
@@ -152,6 +215,12 @@ func randWithFormat(ctx *cli.Context, format string, length int) error {
 		if err != nil {
 			return err
 		}
+		if ctx.Bool("json") {
+			// Raw output is arbitrary bytes, not necessarily valid UTF-8;
+			// base64-encode it rather than round-tripping through a Go
+			// string, which json.Marshal would otherwise mangle.
+			return printResult(ctx, base64.StdEncoding.EncodeToString(b), "base64", format, length, formatEntropyBits(format, length))
+		}
 		os.Stdout.Write(b)
 		return nil
 	}
@@ -185,6 +254,24 @@ func randWithFormat(ctx *cli.Context, format string, length int) error {
 			return err
 		}
 		s = bn.Add(bn, big.NewInt(1)).String()
+	case "base32":
+		s, err = randomBase32(length, false)
+	case "base32hex":
+		s, err = randomBase32(length, true)
+	case "base64":
+		s, err = randomBase64(length, false)
+	case "base64url":
+		s, err = randomBase64(length, true)
+	case "base58":
+		s, err = randomBase58(length)
+	case "z-base-32":
+		s, err = randomZBase32(length)
+	case "mnemonic":
+		bits, err := mnemonicEntropyBits(ctx, length)
+		if err != nil {
+			return err
+		}
+		return randMnemonic(ctx, bits)
 	default:
 		return errs.InvalidFlagValue(ctx, "format", format, "")
 	}
@@ -193,39 +280,35 @@ func randWithFormat(ctx *cli.Context, format string, length int) error {
 		return err
 	}
 
-	fmt.Println(s)
-	return nil
+	return printResult(ctx, s, "", format, length, formatEntropyBits(format, length))
 }
 
-func randWithDictionary(dictionary string, length int) error {
-	file, err := os.Open(dictionary)
+func randWithDictionary(ctx *cli.Context, dictionary string, length int) error {
+	words, err := loadWordlist(dictionary)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	words := make([]string, 0, 1024)
-	for scanner.Scan() {
-		words = append(words, strings.TrimSpace(scanner.Text()))
-	}
-	if err := scanner.Err(); err != nil {
-		return err
+	minSize := ctx.Int("min-dictionary-size")
+	if len(words) < minSize && !ctx.Bool("allow-weak-dictionary") {
+		return fmt.Errorf("dictionary %q only has %d unique words, below the minimum of %d; pass --allow-weak-dictionary to use it anyway", dictionary, len(words), minSize)
 	}
 
-	var s string
-
-	for i := 0; i < length; i++ {
-		bn, err := rand.Int(rand.Reader, big.NewInt(int64(len(words))))
+	if minEntropy := ctx.String("min-entropy"); minEntropy != "" {
+		bits, err := strconv.ParseFloat(minEntropy, 64)
 		if err != nil {
-			return err
-		}
-		s += words[bn.Int64()]
-		if i != length-1 {
-			s += "-"
+			return errs.InvalidFlagValue(ctx, "min-entropy", minEntropy, "")
 		}
+		length = wordsForEntropy(bits, len(words))
+	}
+
+	picked, err := pickWords(words, length)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(s)
-	return nil
+	reportEntropy(length, len(words))
+
+	s := joinWords(picked, ctx.String("separator"), ctx.Bool("capitalize"))
+	return printResult(ctx, s, "", "dictionary", length, float64(length)*bitsPerWord(len(words)))
 }
@@ -0,0 +1,79 @@
+package rand
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/urfave/cli"
+)
+
+// result is the --json output shape: the generated value alongside enough
+// metadata for a script to judge its strength without re-deriving it.
+type result struct {
+	Value         string  `json:"value"`
+	ValueEncoding string  `json:"valueEncoding,omitempty"`
+	Format        string  `json:"format"`
+	Length        int     `json:"length"`
+	EntropyBits   float64 `json:"entropyBits"`
+}
+
+// printResult writes value to stdout, either as a bare line or, when
+// ctx.Bool("json") is set, as a result object carrying format, length, and
+// entropyBits alongside it. valueEncoding names how value is encoded when
+// it isn't plain text as-is (e.g. "base64" for raw bytes that round-tripped
+// through json.Marshal would otherwise corrupt non-UTF-8 output); pass ""
+// for formats whose value is already the literal string to print.
+func printResult(ctx *cli.Context, value, valueEncoding, format string, length int, entropyBits float64) error {
+	if !ctx.Bool("json") {
+		fmt.Println(value)
+		return nil
+	}
+
+	b, err := json.Marshal(result{Value: value, ValueEncoding: valueEncoding, Format: format, Length: length, EntropyBits: entropyBits})
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// formatEntropyBits returns the entropy, in bits, of a length-character
+// string generated in format, or 0 for a format it has no case for
+// (mnemonic included).
+func formatEntropyBits(format string, length int) float64 {
+	switch format {
+	case "", "ascii":
+		return float64(length) * math.Log2(94)
+	case "alphanumeric":
+		return float64(length) * math.Log2(62)
+	case "alphabet":
+		return float64(length) * math.Log2(52)
+	case "hex", "hexadecimal":
+		return float64(length) * math.Log2(16)
+	case "dec", "decimal":
+		return float64(length) * math.Log2(10)
+	case "lower", "upper":
+		return float64(length) * math.Log2(26)
+	case "emoji":
+		return float64(length) * math.Log2(256)
+	case "raw":
+		return float64(length) * 8
+	case "uuid":
+		return 122
+	case "dice":
+		return math.Log2(float64(length))
+	case "base32":
+		return float64(length) * math.Log2(32)
+	case "base32hex":
+		return float64(length) * math.Log2(32)
+	case "base64", "base64url":
+		return float64(length) * math.Log2(64)
+	case "base58":
+		return float64(length) * math.Log2(58)
+	case "z-base-32":
+		return float64(length) * math.Log2(32)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,165 @@
+package rand
+
+import (
+	"bufio"
+	"crypto/rand"
+	_ "embed"
+	"fmt"
+	"math"
+	"math/bits"
+	"os"
+	"strings"
+)
+
+//go:embed wordlists/eff_large.txt
+var effLargeWordlist string
+
+//go:embed wordlists/eff_short1.txt
+var effShort1Wordlist string
+
+//go:embed wordlists/eff_short2.txt
+var effShort2Wordlist string
+
+// defaultMinDictionarySize is the default value of --min-dictionary-size:
+// the minimum number of unique words a dictionary or wordlist must have,
+// unless --allow-weak-dictionary is set.
+const defaultMinDictionarySize = 1024
+
+// loadWordlist returns the deduplicated words for name, which is either one
+// of the built-in "eff-large", "eff-short1", "eff-short2" lists or a path to
+// a user-supplied file with one word per line.
+func loadWordlist(name string) ([]string, error) {
+	switch name {
+	case "eff-large":
+		return dedupe(splitWords(effLargeWordlist)), nil
+	case "eff-short1":
+		return dedupe(splitWords(effShort1Wordlist)), nil
+	case "eff-short2":
+		return dedupe(splitWords(effShort2Wordlist)), nil
+	default:
+		words, err := readWordlistFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return dedupe(words), nil
+	}
+}
+
+func splitWords(s string) []string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	words := make([]string, 0, len(lines))
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		// Diceware lists are conventionally "11111\tword"; keep only the word.
+		if i := strings.LastIndexByte(l, '\t'); i != -1 {
+			l = l[i+1:]
+		}
+		words = append(words, l)
+	}
+	return words
+}
+
+func readWordlistFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	var words []string
+	for scanner.Scan() {
+		if w := strings.TrimSpace(scanner.Text()); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// dedupe returns words with duplicates removed, preserving first occurrence.
+func dedupe(words []string) []string {
+	seen := make(map[string]struct{}, len(words))
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		out = append(out, w)
+	}
+	return out
+}
+
+// bitsPerWord returns log2(n), the entropy in bits contributed by each word
+// drawn uniformly from an n-word list.
+func bitsPerWord(n int) float64 {
+	return math.Log2(float64(n))
+}
+
+// wordsForEntropy returns the minimum number of words needed to reach at
+// least minBits of entropy when drawing from an n-word list.
+func wordsForEntropy(minBits float64, n int) int {
+	return int(math.Ceil(minBits / bitsPerWord(n)))
+}
+
+// reportEntropy prints the strength of a length-word passphrase drawn from
+// an n-word list to stderr, leaving stdout free for the passphrase itself.
+func reportEntropy(length, n int) {
+	fmt.Fprintf(os.Stderr, "entropy: %d words from a %d-word list ≈ %.1f bits\n", length, n, float64(length)*bitsPerWord(n))
+}
+
+// pickWords draws count words from words in a single crypto/rand read of
+// count*⌈log2(len(words))/8⌉ bytes, rejecting any chunk that doesn't land in
+// [0, len(words)) so every word keeps a uniform probability despite
+// len(words) not being a power of two. Rejections are rare enough that a
+// second read is only needed in the unlucky case.
+func pickWords(words []string, count int) ([]string, error) {
+	n := len(words)
+	if n == 0 {
+		return nil, fmt.Errorf("rand: wordlist is empty")
+	}
+	if n == 1 {
+		// bits.Len(uint(n-1)) is 0 here, which would zero out byteLen below
+		// and index an empty buf; a single-word list has no entropy to draw
+		// anyway, so every pick is just that word.
+		picked := make([]string, count)
+		for i := range picked {
+			picked[i] = words[0]
+		}
+		return picked, nil
+	}
+
+	bitLen := bits.Len(uint(n - 1))
+	byteLen := (bitLen + 7) / 8
+	mask := byte(0xff >> uint(byteLen*8-bitLen))
+
+	picked := make([]string, 0, count)
+	buf := make([]byte, byteLen*count)
+	for len(picked) < count {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for i := 0; i+byteLen <= len(buf) && len(picked) < count; i += byteLen {
+			v := int(buf[i] & mask)
+			for _, b := range buf[i+1 : i+byteLen] {
+				v = v<<8 | int(b)
+			}
+			if v < n {
+				picked = append(picked, words[v])
+			}
+		}
+	}
+	return picked, nil
+}
+
+func joinWords(words []string, separator string, capitalize bool) string {
+	if capitalize {
+		for i, w := range words {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, separator)
+}
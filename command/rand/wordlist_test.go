@@ -0,0 +1,88 @@
+package rand
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWordsForEntropy(t *testing.T) {
+	// A 4-word list contributes exactly 2 bits/word, so boundaries land on
+	// whole words.
+	cases := []struct {
+		minBits float64
+		n       int
+		want    int
+	}{
+		{minBits: 0, n: 4, want: 0},
+		{minBits: 1, n: 4, want: 1},
+		{minBits: 2, n: 4, want: 1},
+		{minBits: 2.01, n: 4, want: 2},
+		{minBits: 4, n: 4, want: 2},
+		{minBits: 4.01, n: 4, want: 3},
+	}
+	for _, c := range cases {
+		if got := wordsForEntropy(c.minBits, c.n); got != c.want {
+			t.Errorf("wordsForEntropy(%v, %d) = %d, want %d", c.minBits, c.n, got, c.want)
+		}
+	}
+}
+
+func TestBitsPerWord(t *testing.T) {
+	if got := bitsPerWord(4); got != 2 {
+		t.Errorf("bitsPerWord(4) = %v, want 2", got)
+	}
+	if got := bitsPerWord(1); got != 0 {
+		t.Errorf("bitsPerWord(1) = %v, want 0", got)
+	}
+}
+
+func TestPickWordsUniformity(t *testing.T) {
+	// A non-power-of-two wordlist exercises the rejection-sampling path in
+	// pickWords; chi-squared-style range check rather than an exact
+	// distribution, since this is a randomized test.
+	words := []string{"a", "b", "c", "d", "e"}
+	const draws = 50000
+
+	counts := make(map[string]int, len(words))
+	picked, err := pickWords(words, draws)
+	if err != nil {
+		t.Fatalf("pickWords() error = %v", err)
+	}
+	if len(picked) != draws {
+		t.Fatalf("pickWords() returned %d words, want %d", len(picked), draws)
+	}
+	for _, w := range picked {
+		counts[w]++
+	}
+
+	want := float64(draws) / float64(len(words))
+	for _, w := range words {
+		got := float64(counts[w])
+		if math.Abs(got-want)/want > 0.05 {
+			t.Errorf("word %q drawn %d times, want ~%.0f (>5%% off uniform)", w, counts[w], want)
+		}
+	}
+}
+
+func TestPickWordsEmptyList(t *testing.T) {
+	if _, err := pickWords(nil, 1); err == nil {
+		t.Error("pickWords(nil, 1) error = nil, want error for empty wordlist")
+	}
+}
+
+func TestPickWordsSingleWordList(t *testing.T) {
+	// bits.Len(uint(n-1)) is 0 when n==1, which used to zero out byteLen and
+	// index an empty buf; this must return the one word instead of panicking.
+	picked, err := pickWords([]string{"only"}, 3)
+	if err != nil {
+		t.Fatalf("pickWords() error = %v", err)
+	}
+	if len(picked) != 3 {
+		t.Fatalf("pickWords() returned %d words, want 3", len(picked))
+	}
+	for _, w := range picked {
+		if w != "only" {
+			t.Errorf("pickWords(single-word list) = %q, want %q", w, "only")
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message/catalog"
+)
+
+// localeMessage is one compiled entry of a per-locale catalog file: the same
+// id cmd/step-i18n-extract assigns in messages.gotext.json, plus the
+// translation a human filled in for that id.
+type localeMessage struct {
+	ID          string `json:"id"`
+	Translation string `json:"translation"`
+}
+
+// localeFile is the compiled catalog for a single locale, e.g. "ja.json" —
+// messages.gotext.json with every message's "translation" field filled in.
+type localeFile struct {
+	Language string          `json:"language"`
+	Messages []localeMessage `json:"messages"`
+}
+
+// LoadCatalog reads every *.json file in dir as a localeFile and returns a
+// catalog.Catalog serving all of them, keyed by each file's "language". Pass
+// the result to RegisterCatalog to make ActiveTranslator locale-aware.
+// Entries with an empty translation are skipped, so a partially translated
+// locale falls back to the original English string.
+func LoadCatalog(dir string) (catalog.Catalog, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := catalog.NewBuilder()
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var lf localeFile
+		if err := json.Unmarshal(b, &lf); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		tag, err := language.Parse(lf.Language)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		for _, m := range lf.Messages {
+			if m.Translation == "" {
+				continue
+			}
+			if err := builder.SetString(tag, m.ID, m.Translation); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", entry.Name(), m.ID, err)
+			}
+		}
+	}
+
+	return builder, nil
+}
+
+// InitI18n adds the --lang global flag to app and arranges for the compiled
+// catalog in localeDir (see LoadCatalog) to be loaded and activated in
+// app.Before, ahead of whatever app.Before already does. The root `step`
+// main is the intended caller; a missing localeDir is not an error, it just
+// leaves ActiveTranslator at NoopTranslator.
+func InitI18n(app *cli.App, localeDir string) {
+	app.Flags = append(app.Flags, cli.StringFlag{
+		Name:  "lang",
+		Usage: "The `locale` (e.g. ja, fr-CA) to render help text in. Defaults to $LC_ALL or $LANG.",
+	})
+
+	next := app.Before
+	app.Before = func(ctx *cli.Context) error {
+		cat, err := LoadCatalog(localeDir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+		} else {
+			RegisterCatalog(cat, ctx.GlobalString("lang"))
+		}
+		if next != nil {
+			return next(ctx)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli"
+)
+
+func TestLoadCatalogRegisterCatalog(t *testing.T) {
+	dir := t.TempDir()
+	locale := `{
+		"language": "ja",
+		"messages": [
+			{"id": "usage.section.options", "translation": "オプション"},
+			{"id": "usage.section.examples", "translation": ""}
+		]
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "ja.json"), []byte(locale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cat, err := LoadCatalog(dir)
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+
+	RegisterCatalog(cat, "ja")
+	t.Cleanup(func() { ActiveTranslator = NoopTranslator{} })
+
+	if got := ActiveTranslator.Translate("usage.section.options", "OPTIONS"); got != "オプション" {
+		t.Errorf("Translate(translated key) = %q, want %q", got, "オプション")
+	}
+	// An empty translation in the locale file is treated as untranslated.
+	if got := ActiveTranslator.Translate("usage.section.examples", "EXAMPLES"); got != "EXAMPLES" {
+		t.Errorf("Translate(empty translation) = %q, want fallback %q", got, "EXAMPLES")
+	}
+}
+
+func TestLoadCatalogMissingDir(t *testing.T) {
+	if _, err := LoadCatalog(filepath.Join(t.TempDir(), "does-not-exist")); !os.IsNotExist(err) {
+		t.Errorf("LoadCatalog(missing dir) error = %v, want os.IsNotExist", err)
+	}
+}
+
+func TestInitI18nAddsLangFlagAndLoadsCatalog(t *testing.T) {
+	dir := t.TempDir()
+	locale := `{"language":"ja","messages":[{"id":"usage.section.options","translation":"オプション"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "ja.json"), []byte(locale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ActiveTranslator = NoopTranslator{} })
+
+	beforeRan := false
+	app := cli.NewApp()
+	app.Before = func(*cli.Context) error {
+		beforeRan = true
+		return nil
+	}
+	InitI18n(app, dir)
+
+	found := false
+	for _, f := range app.Flags {
+		if f.GetName() == "lang" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("InitI18n did not add a --lang flag")
+	}
+
+	app.Action = func(*cli.Context) error { return nil }
+	if err := app.Run([]string{"step", "--lang", "ja"}); err != nil {
+		t.Fatalf("app.Run() error = %v", err)
+	}
+	if !beforeRan {
+		t.Error("InitI18n overwrote app.Before instead of chaining it")
+	}
+	if got := ActiveTranslator.Translate("usage.section.options", "OPTIONS"); got != "オプション" {
+		t.Errorf("Translate() after app.Run = %q, want %q", got, "オプション")
+	}
+}
@@ -0,0 +1,163 @@
+package usage
+
+import (
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Translator renders a string identified by a stable key, falling back to
+// original when the active locale has no translation for it.
+type Translator interface {
+	Translate(key, original string) string
+}
+
+// NoopTranslator is the default Translator: it always returns the original
+// English string untouched.
+type NoopTranslator struct{}
+
+// Translate implements Translator.
+func (NoopTranslator) Translate(_, original string) string {
+	return original
+}
+
+// CatalogTranslator renders strings through a golang.org/x/text/message
+// catalog compiled from per-locale messages.gotext.json files.
+type CatalogTranslator struct {
+	printer *message.Printer
+}
+
+// NewCatalogTranslator returns a CatalogTranslator that resolves keys for tag
+// against cat.
+func NewCatalogTranslator(tag language.Tag, cat catalog.Catalog) *CatalogTranslator {
+	return &CatalogTranslator{printer: message.NewPrinter(tag, message.Catalog(cat))}
+}
+
+// Translate implements Translator. It returns original if key has no entry
+// in the catalog for the translator's locale.
+func (t *CatalogTranslator) Translate(key, original string) string {
+	if t == nil || t.printer == nil {
+		return original
+	}
+	// The fallback is rendered as a format string; escape '%' so an
+	// untranslated original comes back unchanged.
+	if s := t.printer.Sprintf(message.Key(key, strings.ReplaceAll(original, "%", "%%"))); s != "" {
+		return s
+	}
+	return original
+}
+
+// ActiveTranslator is used by HelpPrinter, htmlHelpPrinter, and
+// markdownHelpPrinter to localize section headings, flag usage strings, and
+// description prose. It defaults to NoopTranslator; RegisterCatalog installs
+// a locale-aware CatalogTranslator once a compiled catalog is available.
+var ActiveTranslator Translator = NoopTranslator{}
+
+// RegisterCatalog installs cat as the source of translations for the locale
+// resolved from langFlag (the --lang global flag), $LC_ALL, or $LANG, in
+// that order. It falls back to NoopTranslator if none of them name a locale
+// known to golang.org/x/text/language. InitI18n is the usual caller: it
+// loads cat from a directory of compiled locale files and calls
+// RegisterCatalog in app.Before, once per run, with the --lang flag it adds.
+func RegisterCatalog(cat catalog.Catalog, langFlag string) {
+	tag := resolveLocale(langFlag)
+	if tag == language.Und {
+		ActiveTranslator = NoopTranslator{}
+		return
+	}
+	ActiveTranslator = NewCatalogTranslator(tag, cat)
+}
+
+func resolveLocale(langFlag string) language.Tag {
+	for _, v := range []string{langFlag, os.Getenv("LC_ALL"), os.Getenv("LANG")} {
+		v = strings.SplitN(v, ".", 2)[0] // drop the encoding, e.g. "ja_JP.UTF-8"
+		v = strings.ReplaceAll(v, "_", "-")
+		if v == "" || v == "C" || v == "POSIX" {
+			continue
+		}
+		if tag, err := language.Parse(v); err == nil {
+			return tag
+		}
+	}
+	return language.Und
+}
+
+// sectionKeys maps the canonical English section headings produced by
+// helpPreprocessor to their stable message-catalog keys.
+var sectionKeys = map[string]string{
+	"NAME":                 "usage.section.name",
+	"USAGE":                "usage.section.usage",
+	"DESCRIPTION":          "usage.section.description",
+	"POSITIONAL ARGUMENTS": "usage.section.positional-arguments",
+	"OPTIONS":              "usage.section.options",
+	"EXAMPLES":             "usage.section.examples",
+}
+
+// localizeSections translates the "## HEADING" lines of s in place, matching
+// on the original English heading so findSectionEnd keeps working regardless
+// of the active locale.
+func localizeSections(s string) string {
+	return sectionNameRe.ReplaceAllStringFunc(s, func(h string) string {
+		name := strings.TrimSpace(strings.TrimPrefix(h, "##"))
+		key, ok := sectionKeys[name]
+		if !ok {
+			return h
+		}
+		return "## " + ActiveTranslator.Translate(key, name)
+	})
+}
+
+// localizeData returns a shallow copy of data with its Usage, UsageText, and
+// Description fields, and those of its Flags, routed through
+// ActiveTranslator before cli.HelpPrinterCustom renders them. Keys follow
+// usage.command.<name>.<field> and usage.flag.<name>.usage.
+func localizeData(data interface{}) interface{} {
+	switch v := data.(type) {
+	case *cli.App:
+		cp := *v
+		cp.Usage = translateCommandField(cp.Name, "usage", cp.Usage)
+		cp.UsageText = translateCommandField(cp.Name, "usageText", cp.UsageText)
+		cp.Description = translateCommandField(cp.Name, "description", cp.Description)
+		cp.Flags = localizeFlags(cp.Flags)
+		return &cp
+	case *cli.Command:
+		cp := *v
+		cp.Usage = translateCommandField(cp.Name, "usage", cp.Usage)
+		cp.UsageText = translateCommandField(cp.Name, "usageText", cp.UsageText)
+		cp.Description = translateCommandField(cp.Name, "description", cp.Description)
+		cp.Flags = localizeFlags(cp.Flags)
+		return &cp
+	default:
+		return data
+	}
+}
+
+func translateCommandField(name, field, original string) string {
+	return ActiveTranslator.Translate("usage.command."+name+"."+field, original)
+}
+
+func localizeFlags(flags []cli.Flag) []cli.Flag {
+	out := make([]cli.Flag, len(flags))
+	for i, f := range flags {
+		name := strings.SplitN(f.GetName(), ",", 2)[0]
+		key := "usage.flag." + name + ".usage"
+		switch fl := f.(type) {
+		case cli.StringFlag:
+			fl.Usage = ActiveTranslator.Translate(key, fl.Usage)
+			out[i] = fl
+		case cli.BoolFlag:
+			fl.Usage = ActiveTranslator.Translate(key, fl.Usage)
+			out[i] = fl
+		case cli.IntFlag:
+			fl.Usage = ActiveTranslator.Translate(key, fl.Usage)
+			out[i] = fl
+		default:
+			out[i] = f
+		}
+	}
+	return out
+}
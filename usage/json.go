@@ -0,0 +1,252 @@
+package usage
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// jsonCommand is the schema jsonHelpPrinter emits: a single machine-readable
+// source that shell-completion generators, IDE plugins, and doc-site
+// builders can all consume instead of re-parsing rendered markdown.
+type jsonCommand struct {
+	Name           string              `json:"name"`
+	Path           string              `json:"path"`
+	Usage          string              `json:"usage"`
+	UsageText      string              `json:"usageText"`
+	Description    string              `json:"description"`
+	Category       string              `json:"category,omitempty"`
+	Hidden         bool                `json:"hidden"`
+	Flags          []jsonFlag          `json:"flags"`
+	PositionalArgs []jsonPositionalArg `json:"positionalArgs"`
+	Subcommands    []jsonCommand       `json:"subcommands"`
+	Examples       []jsonExample       `json:"examples"`
+}
+
+type jsonFlag struct {
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Usage    string   `json:"usage"`
+	Type     string   `json:"type"`
+	Default  string   `json:"default,omitempty"`
+	EnvVars  []string `json:"envVars,omitempty"`
+	Required bool     `json:"required"`
+}
+
+type jsonPositionalArg struct {
+	Name     string `json:"name"`
+	Usage    string `json:"usage"`
+	Required bool   `json:"required"`
+	Variadic bool   `json:"variadic"`
+}
+
+type jsonExample struct {
+	Description string `json:"description"`
+	Code        string `json:"code"`
+}
+
+// jsonHelpPrinter renders help as the jsonCommand schema. Flags and
+// subcommands come straight from the cli.App/cli.Command tree; positional
+// arguments and examples are parsed out of the same preprocessed markdown
+// helpPreprocessor already produces for HTML and Markdown, so all three
+// formats stay in sync.
+func jsonHelpPrinter(w io.Writer, templ string, data interface{}) []byte {
+	// capOnlyFirst=false: sectionBody looks sections up by their all-caps
+	// headings, which "## Positional arguments"-style headings would miss.
+	b := helpPreprocessor(w, templ, data, false)
+	s := string(b)
+
+	var doc jsonCommand
+	switch v := data.(type) {
+	case *cli.App:
+		doc = jsonCommand{
+			Name:        v.Name,
+			Path:        v.Name,
+			Usage:       v.Usage,
+			UsageText:   v.UsageText,
+			Description: v.Description,
+			Flags:       jsonFlags(v.Flags),
+			Subcommands: jsonSubcommands(v.Name, v.Commands),
+		}
+	case *cli.Command:
+		doc = jsonCommand{
+			Name:        v.Name,
+			Path:        v.HelpName,
+			Usage:       v.Usage,
+			UsageText:   v.UsageText,
+			Description: v.Description,
+			Category:    v.Category,
+			Hidden:      v.Hidden,
+			Flags:       jsonFlags(v.Flags),
+			Subcommands: jsonSubcommands(v.HelpName, v.Subcommands),
+		}
+	}
+	doc.PositionalArgs = parsePositionalArgs(s)
+	doc.Examples = parseExamples(s)
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	w.Write(out)
+	return out
+}
+
+// jsonSubcommands recurses through cmds, rendering each one's own help so
+// its positional arguments and examples can be parsed the same way as the
+// top-level command.
+func jsonSubcommands(parentPath string, cmds []cli.Command) []jsonCommand {
+	out := make([]jsonCommand, 0, len(cmds))
+	for _, c := range cmds {
+		path := c.Name
+		if parentPath != "" {
+			path = parentPath + "." + c.Name
+		}
+
+		b := helpPreprocessor(io.Discard, cli.CommandHelpTemplate, &c, false)
+		s := string(b)
+
+		out = append(out, jsonCommand{
+			Name:           c.Name,
+			Path:           path,
+			Usage:          c.Usage,
+			UsageText:      c.UsageText,
+			Description:    c.Description,
+			Category:       c.Category,
+			Hidden:         c.Hidden,
+			Flags:          jsonFlags(c.Flags),
+			PositionalArgs: parsePositionalArgs(s),
+			Examples:       parseExamples(s),
+			Subcommands:    jsonSubcommands(path, c.Subcommands),
+		})
+	}
+	return out
+}
+
+func jsonFlags(flags []cli.Flag) []jsonFlag {
+	out := make([]jsonFlag, 0, len(flags))
+	for _, f := range flags {
+		parts := strings.Split(f.GetName(), ",")
+		aliases := make([]string, 0, len(parts)-1)
+		for _, p := range parts[1:] {
+			aliases = append(aliases, strings.TrimSpace(p))
+		}
+		jf := jsonFlag{Name: strings.TrimSpace(parts[0]), Aliases: aliases}
+
+		switch fl := f.(type) {
+		case cli.StringFlag:
+			jf.Usage, jf.Type, jf.Default = fl.Usage, "string", fl.Value
+			jf.EnvVars = splitEnvVar(fl.EnvVar)
+		case cli.BoolFlag:
+			jf.Usage, jf.Type = fl.Usage, "bool"
+			jf.EnvVars = splitEnvVar(fl.EnvVar)
+		case cli.IntFlag:
+			jf.Usage, jf.Type, jf.Default = fl.Usage, "int", strconv.Itoa(fl.Value)
+			jf.EnvVars = splitEnvVar(fl.EnvVar)
+		default:
+			jf.Type = "unknown"
+		}
+		out = append(out, jf)
+	}
+	return out
+}
+
+func splitEnvVar(envVar string) []string {
+	if envVar == "" {
+		return nil
+	}
+	return strings.Split(envVar, ",")
+}
+
+var (
+	// The optional leading "[" and trailing "]" come from markdownify
+	// turning "[<name>]" into "[`name`]"; a positional argument written
+	// without them, e.g. "`name`", is required.
+	positionalArgNameRe = regexp.MustCompile("^(\\[)?`([^`]+)`(\\])?$")
+	fencedCodeBlockRe   = regexp.MustCompile("(?s)```[a-zA-Z]*\n(.*?)```")
+)
+
+// parsePositionalArgs extracts the `name` / description pairs out of the
+// "## POSITIONAL ARGUMENTS" definition list that markdownify produces.
+func parsePositionalArgs(s string) []jsonPositionalArg {
+	section, ok := sectionBody("POSITIONAL ARGUMENTS", s)
+	if !ok {
+		return nil
+	}
+
+	var args []jsonPositionalArg
+	lines := strings.Split(section, "\n")
+	for i, line := range lines {
+		m := positionalArgNameRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		var usage []string
+		for _, l := range lines[i+1:] {
+			if positionalArgNameRe.MatchString(strings.TrimSpace(l)) || strings.HasPrefix(l, "##") {
+				break
+			}
+			usage = append(usage, strings.TrimPrefix(strings.TrimSpace(l), ":"))
+		}
+
+		name := m[2]
+		args = append(args, jsonPositionalArg{
+			Name:     strings.TrimSuffix(name, "..."),
+			Usage:    strings.TrimSpace(strings.Join(usage, " ")),
+			Required: m[1] == "",
+			Variadic: strings.HasSuffix(name, "..."),
+		})
+	}
+	return args
+}
+
+// parseExamples extracts {description, code} pairs from the "## EXAMPLES"
+// section: the prose immediately before a fenced code block is its
+// description.
+func parseExamples(s string) []jsonExample {
+	section, ok := sectionBody("EXAMPLES", s)
+	if !ok {
+		return nil
+	}
+
+	var examples []jsonExample
+	prevEnd := 0
+	for _, m := range fencedCodeBlockRe.FindAllStringSubmatchIndex(section, -1) {
+		examples = append(examples, jsonExample{
+			Description: strings.TrimSpace(section[prevEnd:m[0]]),
+			Code:        strings.TrimSpace(section[m[2]:m[3]]),
+		})
+		prevEnd = m[1]
+	}
+	return examples
+}
+
+// sectionBody returns the body of the "## h" section of s, excluding its
+// heading, reusing the same boundaries findSectionEnd computes for the
+// OPTIONS-reordering pass in helpPreprocessor. s has already been through
+// localizeSections, so h is translated the same way before searching.
+func sectionBody(h, s string) (string, bool) {
+	heading := h
+	if key, ok := sectionKeys[h]; ok {
+		heading = ActiveTranslator.Translate(key, h)
+	}
+
+	start := strings.Index(s, "## "+heading)
+	if start == -1 {
+		return "", false
+	}
+	end := findSectionEnd(heading, s)
+	if end == -1 {
+		end = len(s)
+	}
+	headingEnd := strings.IndexByte(s[start:end], '\n')
+	if headingEnd == -1 {
+		return "", false
+	}
+	return s[start+headingEnd+1 : end], true
+}
@@ -0,0 +1,24 @@
+package usage
+
+import "testing"
+
+func TestParsePositionalArgs(t *testing.T) {
+	s := "## POSITIONAL ARGUMENTS\n\n" +
+		"[`length`]\n" +
+		":  The length of the random string.\n\n" +
+		"`file...`\n" +
+		":  One or more files to read.\n\n" +
+		"## EXAMPLES\n"
+
+	args := parsePositionalArgs(s)
+	if len(args) != 2 {
+		t.Fatalf("parsePositionalArgs() returned %d args, want 2", len(args))
+	}
+
+	if got := args[0]; got.Name != "length" || got.Required || got.Variadic {
+		t.Errorf("args[0] = %+v, want {Name: length, Required: false, Variadic: false}", got)
+	}
+	if got := args[1]; got.Name != "file" || !got.Required || !got.Variadic {
+		t.Errorf("args[1] = %+v, want {Name: file, Required: true, Variadic: true}", got)
+	}
+}
@@ -0,0 +1,147 @@
+package usage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// manHelpPrinter renders help as a roff/groff man page, reusing the same
+// canonical section ordering helpPreprocessor already produces for HTML and
+// Markdown so man pages match the on-screen flag order.
+func manHelpPrinter(w io.Writer, templ string, section int, data interface{}) []byte {
+	// capOnlyFirst=false: man pages use the conventional all-caps
+	// .SH NAME/SYNOPSIS/DESCRIPTION/OPTIONS/EXAMPLES, unlike the
+	// "## Options"-style prose headings HTML/Markdown render.
+	b := helpPreprocessor(w, templ, data, false)
+	roff := manify(b, section, commandName(data))
+	w.Write(roff)
+	return roff
+}
+
+// GenerateMan renders one roff document per command in app's tree, including
+// app itself, keyed by the step-<path> name cmd/step-gen-man uses for the
+// resulting step-<subcommand>.1 file.
+func GenerateMan(app *cli.App, section int) map[string][]byte {
+	pages := make(map[string][]byte)
+
+	pages["step"] = manHelpPrinter(new(bytes.Buffer), cli.AppHelpTemplate, section, app)
+
+	for _, node := range Walk(app) {
+		cmd := node.Command
+		name := "step-" + strings.ReplaceAll(node.Path, ".", "-")
+		pages[name] = manHelpPrinter(new(bytes.Buffer), cli.CommandHelpTemplate, section, &cmd)
+	}
+
+	return pages
+}
+
+// ManRequested toggles whether HelpPrinter renders a man page instead of its
+// usual markdown. EnableManFlag is the intended way to set it: it adds a
+// hidden --man flag to app and flips ManRequested from app.Before, the same
+// pattern InitI18n uses for --lang. The root step main (not part of this
+// checkout) is the intended caller, wiring this alongside
+// `cli.HelpPrinter = usage.HelpPrinter`.
+var ManRequested bool
+
+// EnableManFlag adds a hidden --man flag to app, chaining into any existing
+// app.Before. When set, "step help" and "step <command> --help" render roff
+// instead of markdown for the rest of the run.
+func EnableManFlag(app *cli.App) {
+	app.Flags = append(app.Flags, cli.BoolFlag{
+		Name:   "man",
+		Usage:  "Print this command's man page (roff) instead of its usual help text.",
+		Hidden: true,
+	})
+
+	next := app.Before
+	app.Before = func(ctx *cli.Context) error {
+		ManRequested = ctx.GlobalBool("man")
+		if next != nil {
+			return next(ctx)
+		}
+		return nil
+	}
+}
+
+func commandName(data interface{}) string {
+	switch v := data.(type) {
+	case *cli.App:
+		return v.HelpName
+	case *cli.Command:
+		return v.HelpName
+	default:
+		return "step"
+	}
+}
+
+var (
+	codeSpanRe = regexp.MustCompile("`([^`]+)`")
+	boldRe     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// manify converts the preprocessed markdown produced by helpPreprocessor
+// into roff, the way markdownify itself converts the raw urfave/cli
+// template output into markdown: a small hand-rolled pass, not a full AST.
+func manify(b []byte, section int, name string) []byte {
+	out := new(bytes.Buffer)
+	fmt.Fprintf(out, ".TH \"%s\" \"%d\"\n", strings.ToUpper(name), section)
+
+	inCode := false
+	for _, line := range strings.Split(string(b), "\n") {
+		switch {
+		case strings.HasPrefix(line, "```"):
+			if inCode {
+				out.WriteString(".EE\n")
+			} else {
+				out.WriteString(".EX\n")
+			}
+			inCode = !inCode
+		case inCode:
+			out.WriteString(escapeRoff(line))
+			out.WriteByte('\n')
+		case strings.HasPrefix(line, "## "):
+			heading := strings.TrimPrefix(line, "## ")
+			if heading == "USAGE" {
+				// Man pages conventionally call this section SYNOPSIS.
+				heading = "SYNOPSIS"
+			}
+			fmt.Fprintf(out, ".SH %s\n", heading)
+		case strings.HasPrefix(line, "# "):
+			// Title is already covered by .TH above.
+		case strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "- "):
+			item := strings.TrimPrefix(strings.TrimPrefix(line, "* "), "- ")
+			fmt.Fprintf(out, ".TP\n%s\n", inlineToRoff(item))
+		case strings.TrimSpace(line) == "":
+			out.WriteString(".PP\n")
+		default:
+			out.WriteString(inlineToRoff(line))
+			out.WriteByte('\n')
+		}
+	}
+
+	return out.Bytes()
+}
+
+// inlineToRoff converts the `code` and **bold** emphasis that markdownify
+// already produced into roff font escapes.
+func inlineToRoff(s string) string {
+	s = escapeRoff(s)
+	s = codeSpanRe.ReplaceAllString(s, `\fI$1\fR`)
+	s = boldRe.ReplaceAllString(s, `\fB$1\fR`)
+	return s
+}
+
+// escapeRoff neutralizes leading dots/quotes and backslashes so help text
+// can't be mistaken for roff requests.
+func escapeRoff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
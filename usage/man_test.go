@@ -0,0 +1,103 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli"
+)
+
+func TestEscapeRoff(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{`no special chars`, `no special chars`},
+		{`back\slash`, `back\\slash`},
+		{`.TH leading dot`, `\&.TH leading dot`},
+		{`'quoted leading`, `\&'quoted leading`},
+	}
+	for _, c := range cases {
+		if got := escapeRoff(c.in); got != c.want {
+			t.Errorf("escapeRoff(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestInlineToRoff(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"a `code` span", `a \fIcode\fR span`},
+		{"a **bold** span", `a \fBbold\fR span`},
+		{"`code` and **bold**", `\fIcode\fR and \fBbold\fR`},
+	}
+	for _, c := range cases {
+		if got := inlineToRoff(c.in); got != c.want {
+			t.Errorf("inlineToRoff(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestManifyHeadingsStayUppercase(t *testing.T) {
+	src := "# step rand\n\n## OPTIONS\n\nSome `code` and **bold** text.\n\n" +
+		"* item one\n\n```\n$ step rand\nabc\n```\n"
+
+	out := string(manify([]byte(src), 1, "step-rand"))
+
+	if !strings.Contains(out, ".TH \"STEP-RAND\" \"1\"") {
+		t.Errorf("manify() missing .TH header, got:\n%s", out)
+	}
+	if !strings.Contains(out, ".SH OPTIONS") {
+		t.Errorf("manify() did not keep heading uppercase, got:\n%s", out)
+	}
+	if !strings.Contains(out, `\fIcode\fR`) || !strings.Contains(out, `\fBbold\fR`) {
+		t.Errorf("manify() did not convert inline emphasis, got:\n%s", out)
+	}
+	if !strings.Contains(out, ".TP") {
+		t.Errorf("manify() did not convert the list item to .TP, got:\n%s", out)
+	}
+	if !strings.Contains(out, ".EX") || !strings.Contains(out, ".EE") {
+		t.Errorf("manify() did not wrap the fenced code block in .EX/.EE, got:\n%s", out)
+	}
+}
+
+func TestEnableManFlagSetsManRequested(t *testing.T) {
+	t.Cleanup(func() { ManRequested = false })
+
+	beforeRan := false
+	app := cli.NewApp()
+	app.Before = func(*cli.Context) error {
+		beforeRan = true
+		return nil
+	}
+	EnableManFlag(app)
+
+	found := false
+	for _, f := range app.Flags {
+		if f.GetName() == "man" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("EnableManFlag did not add a --man flag")
+	}
+
+	app.Action = func(*cli.Context) error { return nil }
+	if err := app.Run([]string{"step", "--man"}); err != nil {
+		t.Fatalf("app.Run() error = %v", err)
+	}
+	if !beforeRan {
+		t.Error("EnableManFlag overwrote app.Before instead of chaining it")
+	}
+	if !ManRequested {
+		t.Error("ManRequested = false after running with --man, want true")
+	}
+
+	if err := app.Run([]string{"step"}); err != nil {
+		t.Fatalf("app.Run() error = %v", err)
+	}
+	if ManRequested {
+		t.Error("ManRequested = true after running without --man, want false")
+	}
+}
@@ -23,8 +23,14 @@ type frontmatterData struct {
 	Parent string
 }
 
-// HelpPrinter overwrites cli.HelpPrinter and prints the formatted help to the terminal.
+// HelpPrinter overwrites cli.HelpPrinter and prints the formatted help to the
+// terminal. When ManRequested is set (see EnableManFlag), it prints a roff
+// man page instead.
 func HelpPrinter(w io.Writer, templ string, data interface{}) {
+	if ManRequested {
+		manHelpPrinter(w, templ, 1, data)
+		return
+	}
 	b := helpPreprocessor(w, templ, data, false)
 	w.Write(Render(b))
 }
@@ -70,6 +76,8 @@ title: {{.Data.HelpName}}
 }
 
 func helpPreprocessor(w io.Writer, templ string, data interface{}, capOnlyFirst bool) []byte {
+	data = localizeData(data)
+
 	buf := new(bytes.Buffer)
 	cli.HelpPrinterCustom(buf, templ, data, nil)
 	//w.Write(buf.Bytes())
@@ -99,6 +107,8 @@ func helpPreprocessor(w io.Writer, templ string, data interface{}, capOnlyFirst
 		}
 	}
 
+	s = localizeSections(s)
+
 	// Keep capitalized only the first letter in arguments names.
 	if capOnlyFirst {
 		s = sectionNameRe.ReplaceAllStringFunc(s, func(s string) string {
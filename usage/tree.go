@@ -0,0 +1,33 @@
+package usage
+
+import "github.com/urfave/cli"
+
+// CommandNode is one node of a flattened cli.App command tree, carrying the
+// dot-separated path to reach it (e.g. "certificate.create") alongside the
+// command itself.
+type CommandNode struct {
+	Path    string
+	Command cli.Command
+}
+
+// Walk flattens app's command tree into a depth-first slice of CommandNode,
+// so tools that need every command and subcommand (step-i18n-extract,
+// step-gen-man) can share one traversal.
+func Walk(app *cli.App) []CommandNode {
+	var nodes []CommandNode
+	var visit func(prefix string, cmds []cli.Command)
+	visit = func(prefix string, cmds []cli.Command) {
+		for _, c := range cmds {
+			path := c.Name
+			if prefix != "" {
+				path = prefix + "." + c.Name
+			}
+			nodes = append(nodes, CommandNode{Path: path, Command: c})
+			if len(c.Subcommands) > 0 {
+				visit(path, c.Subcommands)
+			}
+		}
+	}
+	visit("", app.Commands)
+	return nodes
+}